@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// actionOutput is a single $GITHUB_OUTPUT key/value pair, kept as an
+// ordered slice rather than a map so output order is deterministic.
+type actionOutput struct {
+	Key   string
+	Value string
+}
+
+// actionsReporter emits GitHub Actions workflow commands when running as
+// a step in a GitHub Actions job, and is a no-op otherwise.
+type actionsReporter struct {
+	enabled bool
+}
+
+// newActionsReporter enables GitHub Actions output when explicitly
+// requested via -actions, or automatically when GITHUB_ACTIONS=true.
+func newActionsReporter(explicit bool) *actionsReporter {
+	return &actionsReporter{enabled: explicit || os.Getenv("GITHUB_ACTIONS") == "true"}
+}
+
+// maskToken registers token as a secret with the Actions log masker. It
+// should be called once, as early as possible.
+func (a *actionsReporter) maskToken(token string) {
+	if !a.enabled || token == "" {
+		return
+	}
+	fmt.Printf("::add-mask::%s\n", token)
+}
+
+func (a *actionsReporter) startGroup(title string) {
+	if a.enabled {
+		fmt.Printf("::group::%s\n", title)
+	}
+}
+
+func (a *actionsReporter) endGroup() {
+	if a.enabled {
+		fmt.Println("::endgroup::")
+	}
+}
+
+func (a *actionsReporter) notice(format string, args ...any) {
+	if a.enabled {
+		fmt.Printf("::notice::%s\n", fmt.Sprintf(format, args...))
+	}
+}
+
+func (a *actionsReporter) warning(format string, args ...any) {
+	if a.enabled {
+		fmt.Printf("::warning::%s\n", fmt.Sprintf(format, args...))
+	}
+}
+
+// errorCommand prints an ::error:: workflow command, optionally scoped to
+// file (e.g. a -selector-file path).
+func (a *actionsReporter) errorCommand(file, format string, args ...any) {
+	if !a.enabled {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if file == "" {
+		fmt.Printf("::error::%s\n", msg)
+		return
+	}
+	fmt.Printf("::error file=%s::%s\n", file, msg)
+}
+
+// writeOutputs appends outputs to $GITHUB_OUTPUT using the multiline
+// <<DELIM file syntax, so values may safely contain newlines.
+func (a *actionsReporter) writeOutputs(outputs []actionOutput) error {
+	if !a.enabled {
+		return nil
+	}
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening GITHUB_OUTPUT: %w", err)
+	}
+	defer f.Close()
+
+	for _, out := range outputs {
+		delim, err := randomDelimiter()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(f, "%s<<%s\n%s\n%s\n", out.Key, delim, out.Value, delim); err != nil {
+			return fmt.Errorf("writing GITHUB_OUTPUT: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeSummary appends a markdown table of the matched operationIds to
+// $GITHUB_STEP_SUMMARY.
+func (a *actionsReporter) writeSummary(groupName string, operationIDs []string) error {
+	if !a.enabled {
+		return nil
+	}
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening GITHUB_STEP_SUMMARY: %w", err)
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### Matched operations for `%s`\n\n", groupName)
+	sb.WriteString("| operationId |\n|---|\n")
+	for _, id := range operationIDs {
+		fmt.Fprintf(&sb, "| %s |\n", id)
+	}
+
+	_, err = f.WriteString(sb.String())
+	return err
+}
+
+// writeBatchSummary appends a markdown table of every batch job's outcome
+// to $GITHUB_STEP_SUMMARY, mirroring printSummary's columns.
+func (a *actionsReporter) writeBatchSummary(results []JobResult) error {
+	if !a.enabled {
+		return nil
+	}
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening GITHUB_STEP_SUMMARY: %w", err)
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	sb.WriteString("### Batch job summary\n\n")
+	sb.WriteString("| group | status | ops | detail |\n|---|---|---|---|\n")
+	for _, r := range results {
+		status, detail := "OK", r.OutputPath
+		if r.Err != nil {
+			status, detail = "FAILED", r.Err.Error()
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %d | %s |\n", r.Job.GroupName, status, r.OperationCount, detail)
+	}
+
+	_, err = f.WriteString(sb.String())
+	return err
+}
+
+func randomDelimiter() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "ghadelim_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,366 @@
+// Package apihub provides a client for the subset of the Apihub REST API
+// used by the operation group creator: listing operations, managing
+// operation groups, and driving spec exports.
+package apihub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to a single Apihub instance on behalf of one personal
+// access token. It is safe to share across concurrently running jobs.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+
+	// Progress, if set, is notified of listing and export-polling
+	// progress. It is nil by default.
+	Progress *ProgressHooks
+
+	// MaxRetries caps how many times a request is retried after a 429 or
+	// 5xx response, or a network timeout. NewClient sets this to
+	// defaultMaxRetries; set to 0 to disable retries entirely.
+	MaxRetries int
+	// RetryBudget caps the total time spent retrying a single request,
+	// on top of MaxRetries. NewClient sets this to defaultRetryBudget;
+	// set to 0 to disable the time-based cap (MaxRetries still applies).
+	RetryBudget time.Duration
+	// PollInterval is how often WaitAndSaveExport polls the export status
+	// endpoint. NewClient sets this to defaultPollInterval.
+	PollInterval time.Duration
+}
+
+// NewClient returns a Client ready to use against baseURL, authenticating
+// requests with token. It uses http.DefaultClient; set HTTPClient to use
+// another.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:      baseURL,
+		Token:        token,
+		HTTPClient:   http.DefaultClient,
+		MaxRetries:   defaultMaxRetries,
+		RetryBudget:  defaultRetryBudget,
+		PollInterval: defaultPollInterval,
+	}
+}
+
+func (c *Client) ListOperations(ctx context.Context, packageID, version string) ([]Operation, error) {
+	var allOps []Operation
+	page := 0
+
+	for {
+		path := fmt.Sprintf(listPath, packageID, version, apiType)
+		reqURL := fmt.Sprintf("%s%s?skipRefs=true&limit=%d&page=%d", c.BaseURL, path, pageSize, page)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.doRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, newStatusError(resp.StatusCode, body)
+		}
+
+		var listResp ListResponse
+		if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+			return nil, err
+		}
+
+		allOps = append(allOps, listResp.Operations...)
+		if c.Progress != nil && c.Progress.OnListPage != nil {
+			c.Progress.OnListPage(len(allOps))
+		}
+
+		if len(listResp.Operations) < pageSize {
+			break
+		}
+		page++
+	}
+
+	if c.Progress != nil && c.Progress.OnListDone != nil {
+		c.Progress.OnListDone(len(allOps))
+	}
+	return allOps, nil
+}
+
+func (c *Client) GroupExists(ctx context.Context, packageID, version, groupName string) (bool, error) {
+	path := fmt.Sprintf(existsPath, packageID, version, apiType, url.PathEscape(groupName))
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return true, nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	statusErr := newStatusError(resp.StatusCode, body)
+	if errors.Is(statusErr, ErrNotFound) {
+		return false, nil
+	}
+	return false, statusErr
+}
+
+func (c *Client) DeleteGroup(ctx context.Context, packageID, version, groupName string) error {
+	path := fmt.Sprintf(deletePath, packageID, version, apiType, url.PathEscape(groupName))
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return newStatusError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (c *Client) CreateGroup(ctx context.Context, packageID, version, groupName string) error {
+	path := fmt.Sprintf(createPath, packageID, version, apiType)
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, path)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("groupName", groupName); err != nil {
+		return err
+	}
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return newStatusError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (c *Client) UpdateGroupOperations(ctx context.Context, packageID, version, groupName string, operations []Operation) error {
+	path := fmt.Sprintf(updatePath, packageID, version, apiType, url.PathEscape(groupName))
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, path)
+
+	operationRefs := make([]OperationRef, len(operations))
+	for i, op := range operations {
+		operationRefs[i] = OperationRef{OperationID: op.OperationID}
+	}
+
+	operationsJSON, err := json.Marshal(operationRefs)
+	if err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormField("operations")
+	if err != nil {
+		return err
+	}
+	part.Write(operationsJSON)
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", reqURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return newStatusError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+func (c *Client) StartExport(ctx context.Context, packageID, version, groupName, outputFormat string) (string, error) {
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, exportPath)
+
+	exportReq := ExportRequest{
+		ExportedEntity:               "restOperationsGroup",
+		PackageID:                    packageID,
+		Version:                      version,
+		GroupName:                    groupName,
+		OperationsSpecTransformation: "reducedSourceSpecifications",
+		Format:                       outputFormat,
+		RemoveOasExtensions:          true,
+	}
+
+	body, err := json.Marshal(exportReq)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return "", newStatusError(resp.StatusCode, body)
+	}
+
+	var result struct {
+		ExportID string `json:"exportId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	return result.ExportID, nil
+}
+
+// WaitAndSaveExport polls the export status until it completes, fails, or
+// ctx is cancelled, then hands the resulting file to sink under name. A
+// transient error talking to the status endpoint (rate limiting or a
+// server error that survived doRequest's own retries) doesn't abort the
+// poll; only a terminal "error" export status, or a non-transient error,
+// does.
+func (c *Client) WaitAndSaveExport(ctx context.Context, exportId string, sink Sink, name string, meta Metadata) error {
+	pollInterval := c.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	if c.Progress != nil && c.Progress.OnPollDone != nil {
+		defer c.Progress.OnPollDone()
+	}
+
+	for {
+		status, fileData, err := c.getExportStatus(ctx, exportId)
+		if err != nil && !isTransientErr(err) {
+			return err
+		}
+
+		if err == nil {
+			if c.Progress != nil && c.Progress.OnPollTick != nil {
+				c.Progress.OnPollTick(status, time.Since(start))
+			}
+
+			switch status {
+			case "completed":
+				if fileData != nil {
+					return sink.Write(ctx, name, fileData, meta)
+				}
+				return fmt.Errorf("export data is empty")
+			case "error":
+				return fmt.Errorf("export failed")
+			case "none":
+				// just wait
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func isTransientErr(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer)
+}
+
+func (c *Client) getExportStatus(ctx context.Context, exportId string) (string, []byte, error) {
+	path := fmt.Sprintf(exportStatusPath, exportId)
+	reqURL := fmt.Sprintf("%s%s", c.BaseURL, path)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", nil, newStatusError(resp.StatusCode, body)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "application/json") {
+		var statusResp ExportStatusResponse
+		if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
+			return "", nil, err
+		}
+		if statusResp.Status == "error" {
+			return statusResp.Status, nil, fmt.Errorf("response message: %s", statusResp.Message)
+		}
+
+		return statusResp.Status, nil, nil
+	}
+
+	fileData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	return "completed", fileData, nil
+}
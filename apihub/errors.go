@@ -0,0 +1,57 @@
+package apihub
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors callers can match against with errors.Is, regardless of
+// the exact status code or response body behind them.
+var (
+	ErrNotFound     = errors.New("apihub: not found")
+	ErrUnauthorized = errors.New("apihub: unauthorized")
+	ErrConflict     = errors.New("apihub: conflict")
+	ErrRateLimited  = errors.New("apihub: rate limited")
+	ErrServer       = errors.New("apihub: server error")
+)
+
+// StatusError is returned whenever an Apihub response has a status code a
+// method didn't expect. It wraps one of the sentinel errors above when the
+// status code falls into a recognized class, so callers can use errors.Is
+// instead of comparing status codes themselves.
+type StatusError struct {
+	StatusCode int
+	Body       string
+
+	sentinel error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("apihub: unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.sentinel
+}
+
+func newStatusError(statusCode int, body []byte) *StatusError {
+	var sentinel error
+	switch {
+	case statusCode == http.StatusNotFound:
+		sentinel = ErrNotFound
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		sentinel = ErrUnauthorized
+	case statusCode == http.StatusConflict:
+		sentinel = ErrConflict
+	case statusCode == http.StatusTooManyRequests:
+		sentinel = ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		sentinel = ErrServer
+	}
+	return &StatusError{StatusCode: statusCode, Body: string(body), sentinel: sentinel}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
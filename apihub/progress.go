@@ -0,0 +1,22 @@
+package apihub
+
+import "time"
+
+// ProgressHooks lets a caller observe the two long-running phases of a job
+// (paginated listing and export-status polling) without the client
+// depending on any particular progress-bar library. Any hook may be nil.
+type ProgressHooks struct {
+	// OnListPage is called after each page of operations is fetched, with
+	// the running total fetched so far.
+	OnListPage func(fetchedSoFar int)
+	// OnListDone is called once listing finishes successfully, with the
+	// final operation count.
+	OnListDone func(total int)
+	// OnPollTick is called on every export-status poll that returns a
+	// status without error, with that status and the time elapsed since
+	// polling began. Polls that fail with a transient error do not call it.
+	OnPollTick func(status string, elapsed time.Duration)
+	// OnPollDone is called once polling stops, whether it succeeded,
+	// failed, or was cancelled.
+	OnPollDone func()
+}
@@ -0,0 +1,107 @@
+package apihub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 5
+	defaultRetryBudget  = 60 * time.Second
+	defaultPollInterval = 5 * time.Second
+
+	backoffBase = 500 * time.Millisecond
+	backoffCap  = 30 * time.Second
+)
+
+// doRequest sends req, retrying on 429, 5xx, and net.Error timeouts with
+// exponential backoff and jitter, honoring a Retry-After header when the
+// server sends one. It gives up once MaxRetries is reached or RetryBudget
+// has elapsed, whichever comes first, and returns whatever response or
+// error it last observed for the caller to interpret.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	req.Header.Set(personalToken, c.Token)
+
+	var deadline time.Time
+	if c.RetryBudget > 0 {
+		deadline = time.Now().Add(c.RetryBudget)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := c.HTTPClient.Do(attemptReq)
+		retriesLeft := attempt < c.MaxRetries && (deadline.IsZero() || time.Now().Before(deadline))
+
+		if err != nil {
+			if !retriesLeft || !isRetryableTransportErr(err) {
+				return nil, err
+			}
+			time.Sleep(backoffDuration(attempt, ""))
+			continue
+		}
+
+		if !isRetryableStatus(resp.StatusCode) || !retriesLeft {
+			return resp, nil
+		}
+
+		retryAfter := resp.Header.Get("Retry-After")
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		time.Sleep(backoffDuration(attempt, retryAfter))
+	}
+}
+
+func isRetryableTransportErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoffDuration computes how long to wait before the next attempt,
+// preferring a server-specified Retry-After (either delta-seconds or an
+// HTTP-date) over exponential backoff with jitter.
+func backoffDuration(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return d
+	}
+
+	d := backoffBase << attempt
+	if d <= 0 || d > backoffCap {
+		d = backoffCap
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(retryAfter); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
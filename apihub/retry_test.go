@@ -0,0 +1,148 @@
+package apihub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoRequestRetriesOnServerErrorThenSucceeds(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+
+	resp, err := client.doRequest(req)
+	if err != nil {
+		t.Fatalf("doRequest returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected final status 200, got %d", resp.StatusCode)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests (2 failures + 1 success), got %d", requests)
+	}
+}
+
+func TestDoRequestGivesUpAfterMaxRetries(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	client.MaxRetries = 2
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+
+	resp, err := client.doRequest(req)
+	if err != nil {
+		t.Fatalf("doRequest returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the last observed status to be returned, got %d", resp.StatusCode)
+	}
+	if requests != 3 { // initial attempt + 2 retries
+		t.Errorf("expected 3 requests (1 initial + 2 retries), got %d", requests)
+	}
+}
+
+func TestGroupExistsDistinguishesNotFoundFromOtherErrors(t *testing.T) {
+	statusCode := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+
+	exists, err := client.GroupExists(context.Background(), "pkg", "v1", "group")
+	if err != nil {
+		t.Fatalf("expected no error for a 404, got %v", err)
+	}
+	if exists {
+		t.Error("expected exists=false for a 404")
+	}
+
+	statusCode = http.StatusUnauthorized
+	_, err = client.GroupExists(context.Background(), "pkg", "v1", "group")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected errors.Is(err, ErrUnauthorized) for a 401, got %v", err)
+	}
+}
+
+func TestWaitAndSaveExportSurvivesTransientPollErrors(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1, 2, 3:
+			// Exhaust doRequest's own retries quickly so getExportStatus
+			// surfaces a transient ErrServer to the poll loop itself, which
+			// must keep polling rather than aborting.
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, "exported spec bytes")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	client.MaxRetries = 0 // let the poll loop itself absorb the transient failures
+	client.PollInterval = time.Millisecond
+
+	sink := &fakeSink{}
+	meta := Metadata{PackageID: "pkg", Version: "v1", GroupName: "group", OutputFormat: "yaml"}
+	if err := client.WaitAndSaveExport(context.Background(), "export-1", sink, "group.yaml", meta); err != nil {
+		t.Fatalf("WaitAndSaveExport returned error: %v", err)
+	}
+
+	if requests < 4 {
+		t.Errorf("expected the poll loop to keep requesting past the transient failures, got %d requests", requests)
+	}
+	if string(sink.data) != "exported spec bytes" {
+		t.Errorf("expected the completed export data to reach the sink, got %q", sink.data)
+	}
+}
+
+// fakeSink records the last artifact written to it.
+type fakeSink struct {
+	name string
+	data []byte
+	meta Metadata
+}
+
+func (s *fakeSink) Write(ctx context.Context, name string, data []byte, meta Metadata) error {
+	s.name, s.data, s.meta = name, data, meta
+	return nil
+}
@@ -0,0 +1,20 @@
+package apihub
+
+import "context"
+
+// Metadata describes the export artifact being written, so a Sink can
+// decide how and where it ends up (e.g. building an S3 key from
+// PackageID/Version rather than trusting name alone).
+type Metadata struct {
+	PackageID    string
+	Version      string
+	GroupName    string
+	OutputFormat string // "yaml" or "json"
+}
+
+// Sink stores a named export artifact - the spec itself, or its sibling
+// operations manifest - wherever the caller wants it to end up: a local
+// file, an S3-compatible bucket, or stdout.
+type Sink interface {
+	Write(ctx context.Context, name string, data []byte, meta Metadata) error
+}
@@ -0,0 +1,43 @@
+package apihub
+
+const (
+	apiType          = "rest"
+	listPath         = "/api/v2/packages/%s/versions/%s/%s/operations"
+	createPath       = "/api/v3/packages/%s/versions/%s/%s/groups"
+	updatePath       = "/api/v3/packages/%s/versions/%s/%s/groups/%s"
+	deletePath       = "/api/v2/packages/%s/versions/%s/%s/groups/%s"
+	existsPath       = "/api/v2/packages/%s/versions/%s/%s/groups/%s"
+	exportPath       = "/api/v1/export"
+	exportStatusPath = "/api/v1/export/%s/status"
+	pageSize         = 100
+	personalToken    = "X-Personal-Access-Token"
+)
+
+type Operation struct {
+	OperationID string         `json:"operationId"`
+	CustomTags  map[string]any `json:"customTags,omitempty"`
+	PackageRef  string         `json:"packageRef"`
+}
+
+type OperationRef struct {
+	OperationID string `json:"operationId"`
+}
+
+type ListResponse struct {
+	Operations []Operation `json:"operations"`
+}
+
+type ExportRequest struct {
+	ExportedEntity               string `json:"exportedEntity"`
+	PackageID                    string `json:"packageId"`
+	Version                      string `json:"version"`
+	GroupName                    string `json:"groupName"`
+	OperationsSpecTransformation string `json:"operationsSpecTransformation"`
+	Format                       string `json:"format"`
+	RemoveOasExtensions          bool   `json:"removeOasExtensions"`
+}
+
+type ExportStatusResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/viacheslav-lunev/apihub-op-group-creator/selector"
+)
+
+// JobConfig describes a single group-creation job within a batch config.
+type JobConfig struct {
+	PackageID    string `yaml:"packageId"`
+	Version      string `yaml:"version"`
+	GroupName    string `yaml:"groupName"`
+	Selector     string `yaml:"selector"`
+	OutputFormat string `yaml:"outputFormat"`
+	OutputPath   string `yaml:"outputPath"`
+
+	// expr is the parsed form of Selector, filled in by LoadBatchConfig.
+	expr selector.Expr
+}
+
+// BatchConfig is the top-level shape of a -config file.
+type BatchConfig struct {
+	Jobs []JobConfig `yaml:"jobs"`
+}
+
+// LoadBatchConfig reads and validates a batch config file, defaulting each
+// job's output format to yaml when omitted.
+func LoadBatchConfig(path string) (*BatchConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var cfg BatchConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	if len(cfg.Jobs) == 0 {
+		return nil, fmt.Errorf("config file defines no jobs")
+	}
+
+	for i, job := range cfg.Jobs {
+		if job.PackageID == "" || job.Version == "" || job.GroupName == "" || job.Selector == "" {
+			return nil, fmt.Errorf("job %d (%s): packageId, version, groupName and selector are required", i, job.GroupName)
+		}
+		expr, err := selector.Parse(job.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("job %d (%s): parsing selector: %w", i, job.GroupName, err)
+		}
+		cfg.Jobs[i].expr = expr
+
+		if job.OutputFormat == "" {
+			cfg.Jobs[i].OutputFormat = "yaml"
+		} else if job.OutputFormat != "yaml" && job.OutputFormat != "json" {
+			return nil, fmt.Errorf("job %d (%s): invalid output format %q", i, job.GroupName, job.OutputFormat)
+		}
+	}
+	return &cfg, nil
+}
+
+// Expr returns the job's parsed selector expression.
+func (j JobConfig) Expr() selector.Expr {
+	return j.expr
+}
@@ -1,460 +1,382 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
-	"net/url"
 	"os"
-	"strings"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
-)
 
-const (
-	apiType          = "rest"
-	listPath         = "/api/v2/packages/%s/versions/%s/%s/operations"
-	createPath       = "/api/v3/packages/%s/versions/%s/%s/groups"
-	updatePath       = "/api/v3/packages/%s/versions/%s/%s/groups/%s"
-	deletePath       = "/api/v2/packages/%s/versions/%s/%s/groups/%s"
-	exportPath       = "/api/v1/export"
-	exportStatusPath = "/api/v1/export/%s/status"
-	pageSize         = 100
-	personalToken    = "X-Personal-Access-Token"
+	"github.com/viacheslav-lunev/apihub-op-group-creator/apihub"
 )
 
-type Operation struct {
-	OperationID string         `json:"operationId"`
-	CustomTags  map[string]any `json:"customTags,omitempty"`
-	PackageRef  string         `json:"packageRef"`
-}
-
-type OperationRef struct {
-	OperationID string `json:"operationId"`
-}
-
-type ListResponse struct {
-	Operations []Operation `json:"operations"`
-}
-
-type ExportRequest struct {
-	ExportedEntity               string `json:"exportedEntity"`
-	PackageID                    string `json:"packageId"`
-	Version                      string `json:"version"`
-	GroupName                    string `json:"groupName"`
-	OperationsSpecTransformation string `json:"operationsSpecTransformation"`
-	Format                       string `json:"format"`
-	RemoveOasExtensions          bool   `json:"removeOasExtensions"`
-}
-
-type ExportStatusResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-}
-
 func main() {
 	apihubUrl := flag.String("apihubURL", "", "Base URL of the Apihub instance")
 	packageID := flag.String("packageId", "", "Package unique identifier (full alias)")
 	version := flag.String("version", "", "Package version")
 	groupName := flag.String("group", "", "Operation group name")
 	apiKey := flag.String("token", "", "Personal API key")
-	customTagKey := flag.String("x-key", "", "Custom tag key")
-	customTagValue := flag.String("x-value", "", "Custom tag value")
+	selectorExpr := flag.String("selector", "", `Tag selector expression, e.g. team=payments AND (tier=public OR tier=partner) AND NOT deprecated=true`)
+	selectorFile := flag.String("selector-file", "", "Path to a file containing a tag selector expression (alternative to -selector)")
+	dryRun := flag.Bool("dry-run", false, "Print matched operationIds without creating or updating the group")
 	force := flag.Bool("force", false, "Recreate group if exists")
 	outputFormat := flag.String("outputFormat", "yaml", "Export output format. Json or Yaml.")
+	configPath := flag.String("config", "", "Path to a batch config file describing multiple group-creation jobs")
+	parallel := flag.Int("parallel", 1, "Number of batch jobs to run concurrently (used with -config)")
+	silent := flag.Bool("silent", false, "Suppress all non-essential output")
+	noProgress := flag.Bool("no-progress", false, "Disable the progress bar/spinner, keeping other log output")
+	maxRetries := flag.Int("max-retries", 5, "Maximum retries for a request after a 429 or 5xx response, or a network timeout")
+	retryBudget := flag.Duration("retry-budget", 60*time.Second, "Maximum total time spent retrying a single request")
+	actionsMode := flag.Bool("actions", false, "Emit GitHub Actions workflow commands (auto-enabled when GITHUB_ACTIONS=true)")
+	sinkKind := flag.String("sink", "fs", "Where to write the exported spec: fs, s3, or stdout (implies -silent -no-progress, so status and progress output stay off stdout; GitHub Actions workflow commands are still written there; not supported with -config)")
+	sinkBucket := flag.String("sink-bucket", "", "Bucket name for -sink=s3")
+	sinkPrefix := flag.String("sink-prefix", "", "Key/path prefix applied by the fs and s3 sinks")
+	sinkEndpoint := flag.String("sink-endpoint", "", "S3-compatible endpoint for -sink=s3 (e.g. s3.amazonaws.com or a MinIO host:port)")
 
 	flag.Parse()
 
-	if *apihubUrl == "" || *packageID == "" || *version == "" || *groupName == "" || *apiKey == "" ||
-		*customTagKey == "" || *customTagValue == "" {
+	reporter := newActionsReporter(*actionsMode)
+	reporter.maskToken(*apiKey)
+
+	if *apihubUrl == "" || *apiKey == "" {
 		fmt.Println("Missing required parameters")
 		flag.Usage()
 		os.Exit(1)
 	}
-	if *outputFormat != "yaml" && *outputFormat != "json" {
-		fmt.Println("Invalid output format")
-		flag.Usage()
-		os.Exit(1)
-	}
 
-	// List all operations
-	operations, err := listOperations(*apihubUrl, *packageID, *version, *apiKey)
+	sink, err := newSink(*sinkKind, *sinkBucket, *sinkPrefix, *sinkEndpoint)
 	if err != nil {
-		fmt.Printf("Error listing operations: %v\n", err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
-
-	fmt.Printf("Operations count: %d\n", len(operations))
-
-	// Filter operations by custom tag
-	filteredOps := filterOperations(operations, *customTagKey, *customTagValue)
-	fmt.Printf("Found %d operations matching conditions\n", len(filteredOps))
-
-	if len(filteredOps) == 0 {
-		fmt.Println("No operations matching criteria found, exiting")
-		return
+	if *sinkKind == "stdout" && *configPath != "" {
+		fmt.Println("-sink=stdout is not supported with -config: concurrent batch jobs would interleave their spec bytes on the shared stdout stream")
+		os.Exit(1)
 	}
 
-	// Re-create group if required
-	if *force {
-		exists, err := groupExists(*apihubUrl, *packageID, *version, *groupName, *apiKey)
-		if err != nil {
-			fmt.Printf("Error checking group existence: %v\n", err)
-			os.Exit(1)
-		} else if exists {
-			if err := deleteGroup(*apihubUrl, *packageID, *version, *groupName, *apiKey); err != nil {
-				fmt.Printf("Error deleting group: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("Existing group deleted")
-		}
-	}
+	// -sink=stdout hands the exported spec bytes to the caller on stdout,
+	// so status and progress output are turned off. GitHub Actions workflow
+	// commands, when enabled, are still written to stdout.
+	pipingToStdout := *sinkKind == "stdout"
+	quiet := *silent || pipingToStdout
+	withoutProgress := *noProgress || pipingToStdout
 
-	// Create new group
-	if err := createGroup(*apihubUrl, *packageID, *version, *groupName, *apiKey); err != nil {
-		fmt.Printf("Error creating group: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Println("Group created successfully")
+	client := apihub.NewClient(*apihubUrl, *apiKey)
+	client.MaxRetries = *maxRetries
+	client.RetryBudget = *retryBudget
 
-	// Update group with operations
-	if err := updateGroupOperations(*apihubUrl, *packageID, *version, *groupName, filteredOps, *apiKey); err != nil {
-		fmt.Printf("Error updating group: %v\n", err)
-		os.Exit(1)
-	}
-	fmt.Printf("Group updated with %d operations\n", len(filteredOps))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Start export
-	exportId, err := startExport(*apihubUrl, *packageID, *version, *groupName, *apiKey, *outputFormat)
-	if err != nil {
-		fmt.Printf("Error starting export: %v\n", err)
-		os.Exit(1)
+	if *configPath != "" {
+		batchCleanup := &batchCleanup{client: client}
+		watchSignals(cancel, batchCleanup)
+		runBatch(ctx, client, batchCleanup, reporter, sink, *configPath, *parallel, quiet)
+		return
 	}
-	fmt.Println("Export started, id:", exportId)
 
-	// Wait for export and save result
-	filePath := fmt.Sprintf("%s.%s", *groupName, *outputFormat)
-	if err := waitAndSaveExport(*apihubUrl, exportId, *apiKey, filePath); err != nil {
-		fmt.Printf("Error during export: %v\n", err)
-		os.Exit(1)
+	cleanup := &cancelCleanup{
+		client:    client,
+		packageID: *packageID,
+		version:   *version,
+		groupName: *groupName,
+		force:     *force,
+	}
+	watchSignals(cancel, cleanup)
+	if !quiet {
+		client.Progress = newProgressHooks(withoutProgress)
 	}
-	fmt.Println("Export result saved to " + filePath)
+	runSingleJob(ctx, client, cleanup, reporter, sink, *packageID, *version, *groupName, *selectorExpr, *selectorFile, *outputFormat, *force, *dryRun, quiet)
 }
 
-func listOperations(apihubUrl, packageID, version, apiKey string) ([]Operation, error) {
-	var allOps []Operation
-	page := 0
-
-	for {
-		path := fmt.Sprintf(listPath, packageID, version, apiType)
-		reqURL := fmt.Sprintf("%s%s?skipRefs=true&limit=%d&page=%d", apihubUrl, path, pageSize, page)
-
-		req, err := http.NewRequest("GET", reqURL, nil)
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set(personalToken, apiKey)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
+// signalCleanup removes whatever groups a run has already created once it's
+// interrupted mid-flight, so a Ctrl-C doesn't leave half-populated groups
+// behind. cancelCleanup implements it for a single job, batchCleanup for
+// a -config run.
+type signalCleanup interface {
+	run()
+}
 
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
-		}
+// watchSignals cancels cancel on SIGINT/SIGTERM. If cleanup is non-nil, it
+// is run before exiting non-zero.
+func watchSignals(cancel context.CancelFunc, cleanup signalCleanup) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-		var listResp ListResponse
-		if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-			return nil, err
-		}
+	go func() {
+		<-sigCh
+		signal.Stop(sigCh)
+		fmt.Println("\nInterrupted, cancelling in-flight requests...")
+		cancel()
 
-		allOps = append(allOps, listResp.Operations...)
-		if len(listResp.Operations) < pageSize {
-			break
+		if cleanup != nil {
+			cleanup.run()
 		}
-		page++
-	}
-	return allOps, nil
+		os.Exit(1)
+	}()
 }
 
-func filterOperations(ops []Operation, customTagKey, customTagValue string) []Operation {
-	var filtered []Operation
-	for _, op := range ops {
-		val, exists := op.CustomTags[customTagKey]
-		if !exists {
-			continue
-		}
-
-		var found bool
-		switch v := val.(type) {
-		case string:
-			found = (v == customTagValue)
-		case []string:
-			for _, s := range v {
-				if s == customTagValue {
-					found = true
-					break
-				}
-			}
-		case []interface{}:
-			for _, elem := range v {
-				if s, ok := elem.(string); ok && s == customTagValue {
-					found = true
-					break
-				}
-			}
-		}
+// cancelCleanup removes the group created by a single job run if it gets
+// interrupted mid-flight and -force was requested.
+type cancelCleanup struct {
+	client    *apihub.Client
+	packageID string
+	version   string
+	groupName string
+	force     bool
 
-		if found {
-			filtered = append(filtered, op)
-		}
-	}
-	return filtered
+	groupCreated atomic.Bool
 }
 
-func groupExists(apihubUrl, packageID, version, groupName, apiKey string) (bool, error) {
-	path := fmt.Sprintf("/api/v2/packages/%s/versions/%s/%s/groups/%s", packageID, version, apiType, url.PathEscape(groupName))
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, path)
+func (c *cancelCleanup) markGroupCreated() {
+	c.groupCreated.Store(true)
+}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return false, err
+func (c *cancelCleanup) run() {
+	if !c.force || !c.groupCreated.Load() {
+		return
 	}
-	req.Header.Set(personalToken, apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return false, err
-	}
-	defer resp.Body.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	if resp.StatusCode == http.StatusOK {
-		return true, nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return false, nil
+	if err := c.client.DeleteGroup(ctx, c.packageID, c.version, c.groupName); err != nil {
+		fmt.Printf("Error deleting group during cleanup: %v\n", err)
+		return
 	}
-	return false, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	fmt.Println("Group deleted during cleanup")
 }
 
-func deleteGroup(apihubUrl, packageID, version, groupName, apiKey string) error {
-	path := fmt.Sprintf(deletePath, packageID, version, apiType, url.PathEscape(groupName))
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, path)
+// batchCleanup removes every group a -config run has created so far if it
+// gets interrupted mid-flight, since a batch job always creates its group
+// from scratch (there's no -force/recreate step to gate on, unlike
+// cancelCleanup). Its methods are safe to call from the concurrent job
+// goroutines runJobs spawns.
+type batchCleanup struct {
+	client *apihub.Client
 
-	req, err := http.NewRequest("DELETE", reqURL, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set(personalToken, apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	mu      sync.Mutex
+	created []createdGroup
+}
 
-	if resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
-	}
-	return nil
+type createdGroup struct {
+	packageID, version, groupName string
 }
 
-func createGroup(apihubUrl, packageID, version, groupName, apiKey string) error {
-	path := fmt.Sprintf(createPath, packageID, version, apiType)
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, path)
+func (b *batchCleanup) markGroupCreated(packageID, version, groupName string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.created = append(b.created, createdGroup{packageID, version, groupName})
+}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+func (b *batchCleanup) run() {
+	b.mu.Lock()
+	created := b.created
+	b.mu.Unlock()
 
-	if err := writer.WriteField("groupName", groupName); err != nil {
-		return err
-	}
-	writer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	req, err := http.NewRequest("POST", reqURL, body)
-	if err != nil {
-		return err
+	for _, g := range created {
+		if err := b.client.DeleteGroup(ctx, g.packageID, g.version, g.groupName); err != nil {
+			fmt.Printf("Error deleting group %q during cleanup: %v\n", g.groupName, err)
+			continue
+		}
+		fmt.Printf("Group %q deleted during cleanup\n", g.groupName)
 	}
+}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set(personalToken, apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
+// runBatch executes every job described by the config file at configPath,
+// running up to parallel jobs concurrently, then prints a summary table.
+// When reporter is enabled, each job failure is also reported as a
+// GitHub Actions ::error:: command and the batch gets its own
+// $GITHUB_OUTPUT entries and $GITHUB_STEP_SUMMARY table. cleanup tracks
+// groups created along the way, so they can be torn down on Ctrl-C. It
+// exits non-zero if any job failed.
+func runBatch(ctx context.Context, client *apihub.Client, cleanup *batchCleanup, reporter *actionsReporter, sink apihub.Sink, configPath string, parallel int, silent bool) {
+	cfg, err := LoadBatchConfig(configPath)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+		fmt.Printf("Error loading config: %v\n", err)
+		reporter.errorCommand(configPath, "loading config: %v", err)
+		os.Exit(1)
 	}
-	return nil
-}
-
-func updateGroupOperations(apihubUrl, packageID, version, groupName string, operations []Operation, apiKey string) error {
-	path := fmt.Sprintf(updatePath, packageID, version, apiType, url.PathEscape(groupName))
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, path)
 
-	// Prepare operations payload
-	operationRefs := make([]OperationRef, len(operations))
-	for i, op := range operations {
-		operationRefs[i] = OperationRef{OperationID: op.OperationID}
+	printIfNotSilent(silent, "Running %d job(s) with parallelism %d\n", len(cfg.Jobs), parallel)
+	results := runJobs(ctx, client, cleanup, sink, reporter, cfg.Jobs, parallel)
+	if !silent {
+		printSummary(results)
 	}
 
-	operationsJSON, err := json.Marshal(operationRefs)
-	if err != nil {
-		return err
+	failures := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failures++
+		}
 	}
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	part, err := writer.CreateFormField("operations")
-	if err != nil {
-		return err
+	outputs := []actionOutput{
+		{Key: "jobCount", Value: fmt.Sprintf("%d", len(results))},
+		{Key: "failedCount", Value: fmt.Sprintf("%d", failures)},
 	}
-	part.Write(operationsJSON)
-	writer.Close()
-
-	req, err := http.NewRequest("PATCH", reqURL, body)
-	if err != nil {
-		return err
+	if err := reporter.writeOutputs(outputs); err != nil {
+		fmt.Printf("Error writing GitHub Actions outputs: %v\n", err)
 	}
-
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set(personalToken, apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
+	if err := reporter.writeBatchSummary(results); err != nil {
+		fmt.Printf("Error writing GitHub Actions job summary: %v\n", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+	if failures > 0 {
+		os.Exit(1)
 	}
-	return nil
 }
 
-func startExport(apihubUrl, packageID, version, groupName, apiKey, outputFormat string) (string, error) {
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, exportPath)
-
-	exportReq := ExportRequest{
-		ExportedEntity:               "restOperationsGroup",
-		PackageID:                    packageID,
-		Version:                      version,
-		GroupName:                    groupName,
-		OperationsSpecTransformation: "reducedSourceSpecifications",
-		Format:                       outputFormat,
-		RemoveOasExtensions:          true,
+// runSingleJob preserves the original one-shot CLI behaviour: list, filter,
+// (re)create, export and save a single operation group. When reporter is
+// enabled, it also emits GitHub Actions workflow commands and populates
+// $GITHUB_OUTPUT/$GITHUB_STEP_SUMMARY.
+func runSingleJob(ctx context.Context, client *apihub.Client, cleanup *cancelCleanup, reporter *actionsReporter, sink apihub.Sink, packageID, version, groupName, selectorExpr, selectorFile, outputFormat string, force, dryRun, silent bool) {
+	if packageID == "" || version == "" || groupName == "" {
+		fmt.Println("Missing required parameters")
+		flag.Usage()
+		os.Exit(1)
 	}
-
-	body, err := json.Marshal(exportReq)
-	if err != nil {
-		return "", err
+	if outputFormat != "yaml" && outputFormat != "json" {
+		fmt.Println("Invalid output format")
+		flag.Usage()
+		os.Exit(1)
 	}
 
-	req, err := http.NewRequest("POST", reqURL, bytes.NewBuffer(body))
+	expr, err := loadSelector(selectorExpr, selectorFile)
 	if err != nil {
-		return "", err
+		fmt.Println(err)
+		reporter.errorCommand(selectorFile, "parsing tag selector: %v", err)
+		flag.Usage()
+		os.Exit(1)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(personalToken, apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	reporter.startGroup("Listing operations")
+	operations, err := client.ListOperations(ctx, packageID, version)
 	if err != nil {
-		return "", err
+		fmt.Printf("Error listing operations: %v\n", err)
+		reporter.errorCommand("", "listing operations: %v", err)
+		os.Exit(1)
 	}
-	defer resp.Body.Close()
+	printIfNotSilent(silent, "Operations count: %d\n", len(operations))
+	reporter.endGroup()
 
-	if resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
-	}
+	reporter.startGroup("Filtering operations")
+	filteredOps := selectOperations(operations, expr)
+	printIfNotSilent(silent, "Found %d operations matching conditions\n", len(filteredOps))
+	reporter.endGroup()
 
-	var result struct {
-		ExportID string `json:"exportId"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+	if len(filteredOps) == 0 {
+		printIfNotSilent(silent, "No operations matching criteria found, exiting\n")
+		reporter.warning("No operations matched the given tag selector, nothing to export")
+		return
 	}
 
-	return result.ExportID, nil
-}
+	if dryRun {
+		for _, op := range filteredOps {
+			fmt.Println(op.OperationID)
+		}
+		return
+	}
 
-func waitAndSaveExport(apihubUrl, exportId, apiKey, filePath string) error {
-	const maxAttempts = 30
-	const sleepDuration = 5 * time.Second
+	reporter.startGroup(fmt.Sprintf("Creating group %q", groupName))
 
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		status, fileData, err := getExportStatus(apihubUrl, exportId, apiKey)
+	// Re-create group if required
+	if force {
+		exists, err := client.GroupExists(ctx, packageID, version, groupName)
 		if err != nil {
-			return err
-		}
-
-		switch status {
-		case "completed":
-			if fileData != nil {
-				return os.WriteFile(filePath, fileData, 0644)
-			} else {
-				return fmt.Errorf("export data is empty")
+			fmt.Printf("Error checking group existence: %v\n", err)
+			reporter.errorCommand("", "checking group existence: %v", err)
+			os.Exit(1)
+		} else if exists {
+			if err := client.DeleteGroup(ctx, packageID, version, groupName); err != nil {
+				fmt.Printf("Error deleting group: %v\n", err)
+				reporter.errorCommand("", "deleting existing group: %v", err)
+				os.Exit(1)
 			}
-		case "error":
-			return fmt.Errorf("export failed")
-		case "none":
-			// just wait
+			printIfNotSilent(silent, "Existing group deleted\n")
 		}
+	}
 
-		time.Sleep(sleepDuration)
+	// Create new group
+	if err := client.CreateGroup(ctx, packageID, version, groupName); err != nil {
+		fmt.Printf("Error creating group: %v\n", err)
+		reporter.errorCommand("", "creating group: %v", err)
+		os.Exit(1)
 	}
+	cleanup.markGroupCreated()
+	printIfNotSilent(silent, "Group created successfully\n")
 
-	return fmt.Errorf("export timed out after %d attempts", maxAttempts)
-}
+	// Update group with operations
+	if err := client.UpdateGroupOperations(ctx, packageID, version, groupName, filteredOps); err != nil {
+		fmt.Printf("Error updating group: %v\n", err)
+		reporter.errorCommand("", "updating group operations: %v", err)
+		os.Exit(1)
+	}
+	printIfNotSilent(silent, "Group updated with %d operations\n", len(filteredOps))
+	reporter.endGroup()
 
-func getExportStatus(apihubUrl, exportId, apiKey string) (string, []byte, error) {
-	path := fmt.Sprintf(exportStatusPath, exportId)
-	reqURL := fmt.Sprintf("%s%s", apihubUrl, path)
+	reporter.startGroup("Exporting group")
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	// Start export
+	exportId, err := client.StartExport(ctx, packageID, version, groupName, outputFormat)
 	if err != nil {
-		return "", nil, err
+		fmt.Printf("Error starting export: %v\n", err)
+		reporter.errorCommand("", "starting export: %v", err)
+		os.Exit(1)
 	}
-	req.Header.Set(personalToken, apiKey)
+	printIfNotSilent(silent, "Export started, id: %s\n", exportId)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", nil, err
+	operationIDs := make([]string, len(filteredOps))
+	for i, op := range filteredOps {
+		operationIDs[i] = op.OperationID
+	}
+
+	// Wait for export and save result
+	name := fmt.Sprintf("%s.%s", groupName, outputFormat)
+	meta := apihub.Metadata{PackageID: packageID, Version: version, GroupName: groupName, OutputFormat: outputFormat}
+	if err := client.WaitAndSaveExport(ctx, exportId, sink, name, meta); err != nil {
+		fmt.Printf("Error during export: %v\n", err)
+		reporter.errorCommand("", "waiting for export: %v", err)
+		os.Exit(1)
 	}
-	defer resp.Body.Close()
+	printIfNotSilent(silent, "Export result saved to %s\n", name)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", nil, fmt.Errorf("unexpected status: %d, body: %s", resp.StatusCode, string(body))
+	opsName := fmt.Sprintf("%s.operations.json", groupName)
+	opsJSON, err := json.Marshal(struct {
+		OperationIDs []string `json:"operationIds"`
+	}{OperationIDs: operationIDs})
+	if err != nil {
+		fmt.Printf("Error marshaling operations manifest: %v\n", err)
+		os.Exit(1)
+	}
+	if err := sink.Write(ctx, opsName, opsJSON, meta); err != nil {
+		fmt.Printf("Error writing operations manifest: %v\n", err)
+		os.Exit(1)
 	}
+	reporter.endGroup()
 
-	contentType := resp.Header.Get("Content-Type")
-	if strings.Contains(contentType, "application/json") {
-		var statusResp ExportStatusResponse
-		if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
-			return "", nil, err
-		}
-		if statusResp.Status == "error" {
-			return statusResp.Status, nil, fmt.Errorf("response message: %s", statusResp.Message)
-		}
+	reporter.notice("Created group %q with %d operations, exported to %s", groupName, len(filteredOps), name)
 
-		return statusResp.Status, nil, nil
+	outputs := []actionOutput{
+		{Key: "exportId", Value: exportId},
+		{Key: "groupName", Value: groupName},
+		{Key: "operationCount", Value: fmt.Sprintf("%d", len(filteredOps))},
+		{Key: "outputPath", Value: name},
+	}
+	if err := reporter.writeOutputs(outputs); err != nil {
+		fmt.Printf("Error writing GitHub Actions outputs: %v\n", err)
 	}
 
-	fileData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", nil, err
+	if err := reporter.writeSummary(groupName, operationIDs); err != nil {
+		fmt.Printf("Error writing GitHub Actions job summary: %v\n", err)
 	}
-	return "completed", fileData, nil
 }
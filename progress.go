@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+
+	"github.com/viacheslav-lunev/apihub-op-group-creator/apihub"
+)
+
+// newProgressHooks wires a pb bar/spinner pair into an apihub.Client for
+// the listing and export-polling phases of a single job. It returns nil
+// when progress output has been disabled.
+func newProgressHooks(noProgress bool) *apihub.ProgressHooks {
+	if noProgress {
+		return nil
+	}
+
+	listBar := pb.New(0)
+	listBar.SetTemplateString(`Listing operations {{counters . }} {{etime . }}`)
+	var listStarted bool
+
+	pollBar := pb.New(0)
+	pollBar.SetTemplateString(`{{ spinner . }} Export status: {{string . "status"}} {{etime . }}`)
+	var pollStarted bool
+
+	return &apihub.ProgressHooks{
+		OnListPage: func(fetchedSoFar int) {
+			if !listStarted {
+				listBar.Start()
+				listStarted = true
+			}
+			listBar.SetCurrent(int64(fetchedSoFar))
+		},
+		OnListDone: func(total int) {
+			if listStarted {
+				listBar.SetCurrent(int64(total))
+				listBar.Finish()
+			}
+		},
+		OnPollTick: func(status string, elapsed time.Duration) {
+			if !pollStarted {
+				pollBar.Start()
+				pollStarted = true
+			}
+			pollBar.Set("status", status)
+		},
+		OnPollDone: func() {
+			if pollStarted {
+				pollBar.Finish()
+			}
+		},
+	}
+}
+
+func printIfNotSilent(silent bool, format string, args ...any) {
+	if silent {
+		return
+	}
+	fmt.Printf(format, args...)
+}
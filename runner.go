@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/viacheslav-lunev/apihub-op-group-creator/apihub"
+)
+
+// JobResult captures the outcome of running a single JobConfig.
+type JobResult struct {
+	Job            JobConfig
+	OperationCount int
+	ExportID       string
+	OutputPath     string
+	Err            error
+}
+
+// runJobs runs jobs against client, at most parallel at a time, and
+// returns one JobResult per job in the same order as jobs. When reporter
+// is enabled, each job failure is reported as a GitHub Actions ::error::
+// workflow command as soon as it's known. cleanup is notified of every
+// group created, so a Ctrl-C can tear them down.
+func runJobs(ctx context.Context, client *apihub.Client, cleanup *batchCleanup, sink apihub.Sink, reporter *actionsReporter, jobs []JobConfig, parallel int) []JobResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]JobResult, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, job JobConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := runJob(ctx, client, cleanup, sink, job)
+			if result.Err != nil {
+				reporter.errorCommand("", "job %q failed: %v", job.GroupName, result.Err)
+			}
+			results[i] = result
+		}(i, job)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runJob(ctx context.Context, client *apihub.Client, cleanup *batchCleanup, sink apihub.Sink, job JobConfig) JobResult {
+	result := JobResult{Job: job}
+
+	operations, err := client.ListOperations(ctx, job.PackageID, job.Version)
+	if err != nil {
+		result.Err = fmt.Errorf("listing operations: %w", err)
+		return result
+	}
+
+	filtered := selectOperations(operations, job.Expr())
+
+	if len(filtered) == 0 {
+		result.Err = fmt.Errorf("no operations matching criteria found")
+		return result
+	}
+	result.OperationCount = len(filtered)
+
+	if err := client.CreateGroup(ctx, job.PackageID, job.Version, job.GroupName); err != nil {
+		result.Err = fmt.Errorf("creating group: %w", err)
+		return result
+	}
+	cleanup.markGroupCreated(job.PackageID, job.Version, job.GroupName)
+
+	if err := client.UpdateGroupOperations(ctx, job.PackageID, job.Version, job.GroupName, filtered); err != nil {
+		result.Err = fmt.Errorf("updating group: %w", err)
+		return result
+	}
+
+	exportId, err := client.StartExport(ctx, job.PackageID, job.Version, job.GroupName, job.OutputFormat)
+	if err != nil {
+		result.Err = fmt.Errorf("starting export: %w", err)
+		return result
+	}
+	result.ExportID = exportId
+
+	outputPath := job.OutputPath
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("%s.%s", job.GroupName, job.OutputFormat)
+	}
+	meta := apihub.Metadata{PackageID: job.PackageID, Version: job.Version, GroupName: job.GroupName, OutputFormat: job.OutputFormat}
+	if err := client.WaitAndSaveExport(ctx, exportId, sink, outputPath, meta); err != nil {
+		result.Err = fmt.Errorf("exporting: %w", err)
+		return result
+	}
+	result.OutputPath = outputPath
+
+	operationIDs := make([]string, len(filtered))
+	for i, op := range filtered {
+		operationIDs[i] = op.OperationID
+	}
+	opsJSON, err := json.Marshal(struct {
+		OperationIDs []string `json:"operationIds"`
+	}{OperationIDs: operationIDs})
+	if err != nil {
+		result.Err = fmt.Errorf("marshaling operations manifest: %w", err)
+		return result
+	}
+	opsName := fmt.Sprintf("%s.operations.json", job.GroupName)
+	if err := sink.Write(ctx, opsName, opsJSON, meta); err != nil {
+		result.Err = fmt.Errorf("writing operations manifest: %w", err)
+		return result
+	}
+
+	return result
+}
+
+func printSummary(results []JobResult) {
+	fmt.Println()
+	fmt.Println("Job summary:")
+	fmt.Printf("%-30s %-8s %-6s %s\n", "GROUP", "STATUS", "OPS", "DETAIL")
+
+	failures := 0
+	for _, r := range results {
+		status, detail := "OK", r.OutputPath
+		if r.Err != nil {
+			status, detail = "FAILED", r.Err.Error()
+			failures++
+		}
+		fmt.Printf("%-30s %-8s %-6d %s\n", r.Job.GroupName, status, r.OperationCount, detail)
+	}
+
+	fmt.Printf("\n%d/%d jobs succeeded\n", len(results)-failures, len(results))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/viacheslav-lunev/apihub-op-group-creator/apihub"
+	"github.com/viacheslav-lunev/apihub-op-group-creator/selector"
+)
+
+// loadSelector resolves the selector expression from either the -selector
+// flag or the -selector-file flag (mutually exclusive) and parses it.
+func loadSelector(selectorExpr, selectorFile string) (selector.Expr, error) {
+	if selectorExpr == "" && selectorFile == "" {
+		return nil, fmt.Errorf("either -selector or -selector-file must be provided")
+	}
+	if selectorExpr != "" && selectorFile != "" {
+		return nil, fmt.Errorf("-selector and -selector-file are mutually exclusive")
+	}
+
+	expr := selectorExpr
+	if selectorFile != "" {
+		data, err := os.ReadFile(selectorFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading selector file: %w", err)
+		}
+		expr = string(data)
+	}
+
+	return selector.Parse(expr)
+}
+
+// selectOperations returns the operations matching the selector expression.
+func selectOperations(ops []apihub.Operation, expr selector.Expr) []apihub.Operation {
+	var filtered []apihub.Operation
+	for _, op := range ops {
+		if selector.Eval(expr, op.CustomTags) {
+			filtered = append(filtered, op)
+		}
+	}
+	return filtered
+}
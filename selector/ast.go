@@ -0,0 +1,45 @@
+// Package selector implements a small boolean expression language over an
+// operation's custom tags, e.g.:
+//
+//	team=payments AND (tier=public OR tier=partner) AND NOT deprecated=true
+//	stability IN {beta,ga} AND path ~ /v2/* AND has(owner)
+package selector
+
+// Expr is a parsed selector expression.
+type Expr interface {
+	isExpr()
+}
+
+// And is satisfied when both Left and Right are satisfied.
+type And struct{ Left, Right Expr }
+
+// Or is satisfied when either Left or Right is satisfied.
+type Or struct{ Left, Right Expr }
+
+// Not is satisfied when X is not satisfied.
+type Not struct{ X Expr }
+
+// Eq is satisfied when the tag Key holds Value, for any of the shapes a
+// tag value may take (a single string or a collection of strings).
+type Eq struct{ Key, Value string }
+
+// In is satisfied when the tag Key holds any of Values.
+type In struct {
+	Key    string
+	Values []string
+}
+
+// Glob is satisfied when the tag Key holds a value matching the glob
+// Pattern (as interpreted by path.Match).
+type Glob struct{ Key, Pattern string }
+
+// Has is satisfied when the tag Key is present, regardless of its value.
+type Has struct{ Key string }
+
+func (And) isExpr()  {}
+func (Or) isExpr()   {}
+func (Not) isExpr()  {}
+func (Eq) isExpr()   {}
+func (In) isExpr()   {}
+func (Glob) isExpr() {}
+func (Has) isExpr()  {}
@@ -0,0 +1,71 @@
+package selector
+
+import "path"
+
+// Eval reports whether tags satisfies expr. tags is an operation's
+// CustomTags map, whose values may be a plain string, a []string, or a
+// []interface{} of strings, mirroring the shapes returned by the Apihub
+// API.
+func Eval(expr Expr, tags map[string]any) bool {
+	switch e := expr.(type) {
+	case And:
+		return Eval(e.Left, tags) && Eval(e.Right, tags)
+	case Or:
+		return Eval(e.Left, tags) || Eval(e.Right, tags)
+	case Not:
+		return !Eval(e.X, tags)
+	case Eq:
+		return containsValue(tagValues(tags[e.Key]), e.Value)
+	case In:
+		values := tagValues(tags[e.Key])
+		for _, want := range e.Values {
+			if containsValue(values, want) {
+				return true
+			}
+		}
+		return false
+	case Glob:
+		for _, v := range tagValues(tags[e.Key]) {
+			if matched, err := path.Match(e.Pattern, v); err == nil && matched {
+				return true
+			}
+		}
+		return false
+	case Has:
+		_, exists := tags[e.Key]
+		return exists
+	default:
+		return false
+	}
+}
+
+// tagValues normalizes a tag value into the set of strings it represents.
+func tagValues(val any) []string {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func containsValue(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokLParen
+	tokRParen
+	tokLBrace
+	tokRBrace
+	tokComma
+	tokEq
+	tokTilde
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input)}
+}
+
+func (l *lexer) peekRune() (rune, bool) {
+	if l.pos >= len(l.input) {
+		return 0, false
+	}
+	return l.input[l.pos], true
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+
+	start := l.pos
+	r, ok := l.peekRune()
+	if !ok {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	switch r {
+	case '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case '{':
+		l.pos++
+		return token{kind: tokLBrace, text: "{", pos: start}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokRBrace, text: "}", pos: start}, nil
+	case ',':
+		l.pos++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case '=':
+		l.pos++
+		return token{kind: tokEq, text: "=", pos: start}, nil
+	case '~':
+		l.pos++
+		return token{kind: tokTilde, text: "~", pos: start}, nil
+	case '"', '\'':
+		return l.lexString(r)
+	default:
+		return l.lexIdent()
+	}
+}
+
+func (l *lexer) lexString(quote rune) (token, error) {
+	start := l.pos
+	l.pos++ // opening quote
+
+	var sb strings.Builder
+	for {
+		r, ok := l.peekRune()
+		if !ok {
+			return token{}, fmt.Errorf("selector: unterminated string starting at position %d", start)
+		}
+		l.pos++
+		if r == '\\' {
+			next, ok := l.peekRune()
+			if !ok {
+				return token{}, fmt.Errorf("selector: unterminated string starting at position %d", start)
+			}
+			l.pos++
+			sb.WriteRune(next)
+			continue
+		}
+		if r == quote {
+			break
+		}
+		sb.WriteRune(r)
+	}
+	return token{kind: tokString, text: sb.String(), pos: start}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	var sb strings.Builder
+	for {
+		r, ok := l.peekRune()
+		if !ok || !isIdentRune(r) {
+			break
+		}
+		sb.WriteRune(r)
+		l.pos++
+	}
+	if sb.Len() == 0 {
+		return token{}, fmt.Errorf("selector: unexpected character %q at position %d", l.input[start], start)
+	}
+	return token{kind: tokIdent, text: sb.String(), pos: start}, nil
+}
+
+func (l *lexer) skipSpace() {
+	for {
+		r, ok := l.peekRune()
+		if !ok || !unicode.IsSpace(r) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// isIdentRune reports whether r may appear in a bare identifier or value:
+// keys and unquoted values commonly look like "x-api-key", "v2", "/v2/*",
+// "beta", or "true", so we accept anything but whitespace and the
+// characters that have structural meaning in the grammar.
+func isIdentRune(r rune) bool {
+	switch r {
+	case '(', ')', '{', '}', ',', '=', '~', '"', '\'':
+		return false
+	}
+	return !unicode.IsSpace(r)
+}
@@ -0,0 +1,219 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse compiles a selector expression, e.g.:
+//
+//	team=payments AND (tier=public OR tier=partner) AND NOT deprecated=true
+//
+// Precedence from loosest to tightest is OR, AND, NOT; parentheses
+// override it as usual.
+func Parse(input string) (Expr, error) {
+	p := &parser{lex: newLexer(input)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("selector: unexpected token %q at position %d", p.tok.text, p.tok.pos)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	return p.tok.kind == tokIdent && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.isKeyword("NOT") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	switch {
+	case p.tok.kind == tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, fmt.Errorf("selector: expected ')' at position %d", p.tok.pos)
+		}
+		return expr, p.advance()
+	case p.isKeyword("has"):
+		return p.parseHas()
+	case p.tok.kind == tokIdent:
+		return p.parseComparison()
+	default:
+		return nil, fmt.Errorf("selector: unexpected token %q at position %d", p.tok.text, p.tok.pos)
+	}
+}
+
+func (p *parser) parseHas() (Expr, error) {
+	if err := p.advance(); err != nil { // consume "has"
+		return nil, err
+	}
+	if p.tok.kind != tokLParen {
+		return nil, fmt.Errorf("selector: expected '(' after has at position %d", p.tok.pos)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokIdent {
+		return nil, fmt.Errorf("selector: expected tag key at position %d", p.tok.pos)
+	}
+	key := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokRParen {
+		return nil, fmt.Errorf("selector: expected ')' at position %d", p.tok.pos)
+	}
+	return Has{Key: key}, p.advance()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	key := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.tok.kind == tokEq:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return Eq{Key: key, Value: value}, nil
+	case p.tok.kind == tokTilde:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		pattern, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return Glob{Key: key, Pattern: pattern}, nil
+	case p.isKeyword("IN"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		values, err := p.parseValueSet()
+		if err != nil {
+			return nil, err
+		}
+		return In{Key: key, Values: values}, nil
+	default:
+		return nil, fmt.Errorf("selector: expected an operator (=, ~, IN) after %q at position %d", key, p.tok.pos)
+	}
+}
+
+func (p *parser) parseValue() (string, error) {
+	if p.tok.kind != tokIdent && p.tok.kind != tokString {
+		return "", fmt.Errorf("selector: expected a value at position %d", p.tok.pos)
+	}
+	value := p.tok.text
+	return value, p.advance()
+}
+
+func (p *parser) parseValueSet() ([]string, error) {
+	if p.tok.kind != tokLBrace {
+		return nil, fmt.Errorf("selector: expected '{' at position %d", p.tok.pos)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		if p.tok.kind != tokComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.kind != tokRBrace {
+		return nil, fmt.Errorf("selector: expected '}' at position %d", p.tok.pos)
+	}
+	return values, p.advance()
+}
@@ -0,0 +1,128 @@
+package selector
+
+import "testing"
+
+func mustParse(t *testing.T, input string) Expr {
+	t.Helper()
+	expr, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) returned error: %v", input, err)
+	}
+	return expr
+}
+
+func TestEvalPrecedence(t *testing.T) {
+	expr := mustParse(t, "team=payments AND tier=public OR tier=partner")
+
+	tests := []struct {
+		name string
+		tags map[string]any
+		want bool
+	}{
+		{"and wins over or on the left", map[string]any{"team": "payments", "tier": "public"}, true},
+		{"or clause alone still matches", map[string]any{"tier": "partner"}, true},
+		{"neither clause matches", map[string]any{"team": "payments", "tier": "internal"}, false},
+		{"and clause missing team", map[string]any{"tier": "public"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Eval(expr, tt.tags); got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalNotAndParens(t *testing.T) {
+	expr := mustParse(t, "team=payments AND (tier=public OR tier=partner) AND NOT deprecated=true")
+
+	if !Eval(expr, map[string]any{"team": "payments", "tier": "public"}) {
+		t.Error("expected match for public, non-deprecated payments operation")
+	}
+	if Eval(expr, map[string]any{"team": "payments", "tier": "public", "deprecated": "true"}) {
+		t.Error("expected no match for deprecated operation")
+	}
+	if Eval(expr, map[string]any{"team": "payments", "tier": "internal"}) {
+		t.Error("expected no match when tier is neither public nor partner")
+	}
+}
+
+func TestEvalIn(t *testing.T) {
+	expr := mustParse(t, "stability IN {beta,ga}")
+
+	if !Eval(expr, map[string]any{"stability": "beta"}) {
+		t.Error("expected beta to match the IN set")
+	}
+	if !Eval(expr, map[string]any{"stability": []interface{}{"alpha", "ga"}}) {
+		t.Error("expected a []interface{} tag containing ga to match")
+	}
+	if Eval(expr, map[string]any{"stability": "alpha"}) {
+		t.Error("expected alpha not to match the IN set")
+	}
+}
+
+func TestEvalGlob(t *testing.T) {
+	expr := mustParse(t, "path ~ /v2/*")
+
+	if !Eval(expr, map[string]any{"path": "/v2/users"}) {
+		t.Error("expected /v2/users to match /v2/*")
+	}
+	if Eval(expr, map[string]any{"path": "/v1/users"}) {
+		t.Error("expected /v1/users not to match /v2/*")
+	}
+}
+
+func TestEvalHas(t *testing.T) {
+	expr := mustParse(t, "has(owner)")
+
+	if !Eval(expr, map[string]any{"owner": "team-a"}) {
+		t.Error("expected has(owner) to match when owner is present")
+	}
+	if Eval(expr, map[string]any{}) {
+		t.Error("expected has(owner) not to match when owner is absent")
+	}
+}
+
+func TestParseQuotedValues(t *testing.T) {
+	expr := mustParse(t, `description = "has spaces, and a comma"`)
+
+	if !Eval(expr, map[string]any{"description": "has spaces, and a comma"}) {
+		t.Error("expected quoted value with spaces and a comma to parse and match verbatim")
+	}
+}
+
+func TestEvalShortCircuits(t *testing.T) {
+	// A false left side decides an AND on its own: the right side is a
+	// malformed glob pattern, which Eval treats as no match, and the
+	// result must be false. Eval has no side effects, so this checks the
+	// result, not whether the right side was evaluated.
+	and := mustParse(t, `missing=1 AND path ~ "["`)
+	if Eval(and, map[string]any{"path": "/v2/users"}) {
+		t.Error("expected AND with a false left side to short-circuit to false")
+	}
+
+	// Symmetrically, a true left side decides an OR on its own, whatever
+	// the malformed right side yields.
+	or := mustParse(t, `present=1 OR path ~ "["`)
+	if !Eval(or, map[string]any{"present": "1", "path": "/v2/users"}) {
+		t.Error("expected OR with a true left side to short-circuit to true")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"team=",
+		"team = payments AND",
+		"(team=payments",
+		"has(owner",
+		"stability IN {beta",
+		"team IN payments",
+	}
+
+	for _, input := range cases {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error, got nil", input)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/viacheslav-lunev/apihub-op-group-creator/apihub"
+)
+
+// newSink builds the export sink selected by -sink. The s3 sink reads
+// credentials from the standard AWS environment variables
+// (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN).
+func newSink(kind, bucket, prefix, endpoint string) (apihub.Sink, error) {
+	switch kind {
+	case "", "fs":
+		return &fsSink{prefix: prefix}, nil
+	case "stdout":
+		return &stdoutSink{}, nil
+	case "s3":
+		if bucket == "" || endpoint == "" {
+			return nil, fmt.Errorf("-sink-bucket and -sink-endpoint are required for -sink=s3")
+		}
+		client, err := minio.New(endpoint, &minio.Options{
+			Creds:  credentials.NewEnvAWS(),
+			Secure: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating S3 client: %w", err)
+		}
+		return &s3Sink{client: client, bucket: bucket, prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink %q, must be fs, s3, or stdout", kind)
+	}
+}
+
+// fsSink writes export artifacts to the local filesystem, optionally
+// rooted under prefix, creating any missing parent directories.
+type fsSink struct {
+	prefix string
+}
+
+func (s *fsSink) Write(ctx context.Context, name string, data []byte, meta apihub.Metadata) error {
+	outPath := name
+	if s.prefix != "" {
+		outPath = filepath.Join(s.prefix, name)
+	}
+	if dir := filepath.Dir(outPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(outPath, data, 0644)
+}
+
+// stdoutSink writes the exported spec to stdout, so the tool can be piped
+// straight into another command (jq, a file, a doc-portal uploader)
+// without touching the filesystem. The operations manifest that
+// accompanies every export goes to stderr instead, with a header
+// identifying it, so stdout carries nothing but the spec bytes.
+type stdoutSink struct{}
+
+func (s *stdoutSink) Write(ctx context.Context, name string, data []byte, meta apihub.Metadata) error {
+	if strings.HasSuffix(name, ".operations.json") {
+		fmt.Fprintf(os.Stderr, "--- %s ---\n", name)
+		_, err := os.Stderr.Write(data)
+		return err
+	}
+	_, err := os.Stdout.Write(data)
+	return err
+}
+
+// s3Sink writes export artifacts to an S3-compatible bucket, keyed by
+// <prefix>/<packageId>/<version>/<name>, so a doc-portal bucket can be
+// fed directly from CI without a separate upload step.
+type s3Sink struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func (s *s3Sink) Write(ctx context.Context, name string, data []byte, meta apihub.Metadata) error {
+	key := path.Join(s.prefix, meta.PackageID, meta.Version, name)
+
+	contentType := "application/yaml"
+	if meta.OutputFormat == "json" || strings.HasSuffix(name, ".json") {
+		contentType = "application/json"
+	}
+
+	_, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}